@@ -0,0 +1,96 @@
+package scylla_cdc
+
+import "reflect"
+
+// ChangeFilter decides whether a single CDC log row should be kept. It is
+// evaluated before the row is appended to a Change's Preimage, Delta or
+// Postimage, so that rows the caller does not care about never pay the cost
+// of being materialized any further than this check.
+type ChangeFilter interface {
+	Match(streamCols streamColumns, c *ChangeRow) bool
+}
+
+// OperationFilter keeps only rows whose operation is one of ops.
+type OperationFilter struct {
+	ops map[OperationType]struct{}
+}
+
+// NewOperationFilter creates an OperationFilter matching any of ops.
+func NewOperationFilter(ops ...OperationType) *OperationFilter {
+	set := make(map[OperationType]struct{}, len(ops))
+	for _, op := range ops {
+		set[op] = struct{}{}
+	}
+	return &OperationFilter{ops: set}
+}
+
+func (f *OperationFilter) Match(streamCols streamColumns, c *ChangeRow) bool {
+	_, ok := f.ops[c.GetOperation()]
+	return ok
+}
+
+// PartitionKeyFilter keeps only rows belonging to one of the given
+// partitions. For a table with a single-column partition key, each element
+// of pkValues is simply that column's value; for a composite partition key,
+// it is the value returned by ChangeRow.GetPartitionKey.
+type PartitionKeyFilter struct {
+	pkValues []interface{}
+}
+
+// NewPartitionKeyFilter creates a PartitionKeyFilter matching any of
+// pkValues.
+func NewPartitionKeyFilter(pkValues ...interface{}) *PartitionKeyFilter {
+	return &PartitionKeyFilter{pkValues: pkValues}
+}
+
+func (f *PartitionKeyFilter) Match(streamCols streamColumns, c *ChangeRow) bool {
+	pk := c.GetPartitionKey()
+	for _, want := range f.pkValues {
+		if reflect.DeepEqual(pk, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldEquals keeps only rows whose column named column has the given
+// value.
+type FieldEquals struct {
+	column string
+	value  interface{}
+}
+
+// NewFieldEquals creates a FieldEquals filter for the given column/value
+// pair.
+func NewFieldEquals(column string, value interface{}) *FieldEquals {
+	return &FieldEquals{column: column, value: value}
+}
+
+func (f *FieldEquals) Match(streamCols streamColumns, c *ChangeRow) bool {
+	if !c.IsColumnSet(f.column) {
+		return false
+	}
+	// Collection columns (list/set/map) come back as slices or maps, which
+	// panic on ==; reflect.DeepEqual handles those the same way as scalars.
+	return reflect.DeepEqual(c.GetValue(f.column), f.value)
+}
+
+// Projection lists which non-primary-key columns a reader should
+// materialize on each ChangeRow; columns not listed here are skipped
+// during scanning. A nil or empty Projection materializes every column, as
+// before.
+type Projection []string
+
+// includes reports whether column should be materialized by this
+// Projection.
+func (p Projection) includes(column string) bool {
+	if len(p) == 0 {
+		return true
+	}
+	for _, c := range p {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
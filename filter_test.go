@@ -0,0 +1,25 @@
+package scylla_cdc
+
+import "testing"
+
+func TestProjectionIncludes(t *testing.T) {
+	var empty Projection
+	if !empty.includes("v1") {
+		t.Error("an empty Projection should include every column")
+	}
+
+	p := Projection{"v1", "v2"}
+	if !p.includes("v1") {
+		t.Error("v1 should be included")
+	}
+	if p.includes("v3") {
+		t.Error("v3 should not be included")
+	}
+}
+
+func TestOperationFilterDeduplicatesOps(t *testing.T) {
+	f := NewOperationFilter(RowDelete, RowDelete, PartitionDelete)
+	if len(f.ops) != 2 {
+		t.Fatalf("expected 2 distinct operations, got %d", len(f.ops))
+	}
+}
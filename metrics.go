@@ -0,0 +1,47 @@
+package scylla_cdc
+
+import "time"
+
+// Metrics is implemented by types which want to observe the internal
+// behavior of readers, e.g. to expose it through Prometheus or any other
+// monitoring backend. All methods must be safe to call concurrently.
+type Metrics interface {
+	// ObserveQueryLatency records how long a single query against the CDC
+	// log table for the given table took, from issuing it until its results
+	// were fully drained (including the ChangeConsumer.Consume calls made
+	// while draining them).
+	ObserveQueryLatency(table string, d time.Duration)
+
+	// ObserveConsumeLatency records how long a single call to
+	// ChangeConsumer.Consume for the given table took.
+	ObserveConsumeLatency(table string, d time.Duration)
+
+	// IncRowsRead increments the number of CDC log rows read for the given
+	// table by n.
+	IncRowsRead(table string, n int)
+
+	// IncChangesConsumed increments the number of changes (complete rows,
+	// after assembling preimage/delta/postimage) consumed for the given
+	// table by n.
+	IncChangesConsumed(table string, n int)
+
+	// IncQueryErrors increments the number of failed queries against the
+	// CDC log table for the given table.
+	IncQueryErrors(table string)
+
+	// IncActiveStreamWorkers reports that one more goroutine started
+	// polling streams for the given table. A table is normally served by
+	// several concurrent stream workers, so this must add to the gauge
+	// rather than overwrite it.
+	IncActiveStreamWorkers(table string)
+
+	// DecActiveStreamWorkers reports that one stream worker for the given
+	// table stopped polling, undoing a prior IncActiveStreamWorkers.
+	DecActiveStreamWorkers(table string)
+
+	// SetLastReadTimestamp reports the raw timestamp of the last change
+	// consumed for the given table. It is stored as-is (not as a
+	// precomputed lag) so that the metric keeps reflecting reality, via
+	// time() - metric, even while no new changes are being consumed.
+	SetLastReadTimestamp(table string, ts time.Time)
+}
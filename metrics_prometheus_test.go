@@ -0,0 +1,44 @@
+package scylla_cdc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusMetricsRecordsObservations(t *testing.T) {
+	m := NewPrometheusMetrics("test")
+
+	m.IncRowsRead("ks.tbl", 3)
+	m.IncChangesConsumed("ks.tbl", 2)
+	m.IncQueryErrors("ks.tbl")
+	m.IncActiveStreamWorkers("ks.tbl")
+	m.IncActiveStreamWorkers("ks.tbl")
+	m.DecActiveStreamWorkers("ks.tbl")
+	m.ObserveQueryLatency("ks.tbl", 10*time.Millisecond)
+	m.ObserveConsumeLatency("ks.tbl", 5*time.Millisecond)
+
+	now := time.Now()
+	m.SetLastReadTimestamp("ks.tbl", now)
+
+	if got := testutil.ToFloat64(m.rowsRead.WithLabelValues("ks.tbl")); got != 3 {
+		t.Errorf("rowsRead = %v, want 3", got)
+	}
+	if got := testutil.ToFloat64(m.changesConsumed.WithLabelValues("ks.tbl")); got != 2 {
+		t.Errorf("changesConsumed = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.queryErrors.WithLabelValues("ks.tbl")); got != 1 {
+		t.Errorf("queryErrors = %v, want 1", got)
+	}
+	// Two Inc calls followed by one Dec: the gauge must reflect the net
+	// count of concurrently active workers, not the last call's intent.
+	if got := testutil.ToFloat64(m.activeStreamWorker.WithLabelValues("ks.tbl")); got != 1 {
+		t.Errorf("activeStreamWorker = %v, want 1", got)
+	}
+	// The metric stores the raw last-read timestamp, not a precomputed lag,
+	// so that it keeps reflecting reality even if scraped long after.
+	if got := testutil.ToFloat64(m.lastReadTimestamp.WithLabelValues("ks.tbl")); got != float64(now.Unix()) {
+		t.Errorf("lastReadTimestamp = %v, want %v", got, now.Unix())
+	}
+}
@@ -0,0 +1,141 @@
+package scylla_cdc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// TableReplicationConfig describes a single table that a MultiTableReader
+// should replicate and how to build the ChangeConsumer for it.
+type TableReplicationConfig struct {
+	Keyspace              string
+	Table                 string
+	ChangeConsumerFactory ChangeConsumerFactory
+}
+
+// MultiTableReader replicates several tables at once, sharing a single
+// generation-discovery loop across all of them: CDC generations and streams
+// are cluster-wide, not per-table, so fetching them once per rotation and
+// fanning the result out to one streamBatchReader per (stream, table) makes
+// every table rotate to the next generation atomically. This replaces the
+// earlier one-Reader-per-table approach, under which each table's
+// independent generation-discovery loop could rotate at a different time
+// from the others.
+type MultiTableReader struct {
+	config *ReaderConfig
+	tables []TableReplicationConfig
+
+	lock          sync.Mutex
+	streamReaders []*streamBatchReader
+}
+
+// NewMultiTableReader creates a MultiTableReader which replicates the given
+// tables. config is used as a template: Session, ProgressManager, Metrics,
+// Logger and Advanced are shared by every table, while ChangeConsumerFactory
+// and the table name are taken from each entry of tables.
+func NewMultiTableReader(config *ReaderConfig, tables []TableReplicationConfig) (*MultiTableReader, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("scylla-cdc: MultiTableReader needs at least one table")
+	}
+
+	return &MultiTableReader{
+		config: config,
+		tables: tables,
+	}, nil
+}
+
+// Run replicates every configured table until ctx is cancelled, Stop/StopNow
+// is called, or a ChangeConsumer returns an error.
+func (m *MultiTableReader) Run(ctx context.Context) error {
+	return runGenerations(
+		ctx,
+		m.config.Session,
+		m.config.Advanced.GenerationPollInterval,
+		m.config.Logger,
+		func(gen gocql.UUID, nextGen *gocql.UUID) ([]*streamBatchReader, error) {
+			// Streams are fetched once per generation and shared by every
+			// table, instead of each table querying
+			// cdc_streams_descriptions_v2 on its own.
+			streams, err := fetchStreams(ctx, m.config.Session, gen)
+			if err != nil {
+				return nil, err
+			}
+
+			readers := make([]*streamBatchReader, 0, len(streams)*len(m.tables))
+			for _, tbl := range m.tables {
+				tableConfig := *m.config
+				tableConfig.ChangeConsumerFactory = tbl.ChangeConsumerFactory
+				baseTableName := tbl.Keyspace + "." + tbl.Table
+
+				for _, streamID := range streams {
+					startFrom, err := resolveStartFrom(ctx, &tableConfig, baseTableName, gen, streamID)
+					if err != nil {
+						return nil, fmt.Errorf("scylla-cdc: failed to resolve start position for %s: %w", baseTableName, err)
+					}
+
+					sbr := newStreamBatchReader(&tableConfig, []StreamID{streamID}, tbl.Keyspace, tbl.Table, gen, startFrom)
+					if nextGen != nil {
+						sbr.close(*nextGen)
+					}
+					readers = append(readers, sbr)
+				}
+			}
+			return readers, nil
+		},
+		func(readers []*streamBatchReader) {
+			m.lock.Lock()
+			m.streamReaders = readers
+			m.lock.Unlock()
+		},
+	)
+}
+
+// Stop gracefully stops every table's readers, letting them process changes
+// up to the current moment before returning.
+func (m *MultiTableReader) Stop() {
+	m.closeReaders(gocql.MinTimeUUID(time.Now()))
+}
+
+// StopNow aborts every table's readers immediately.
+func (m *MultiTableReader) StopNow() {
+	m.closeReaders(gocql.UUID{})
+}
+
+func (m *MultiTableReader) closeReaders(processUntil gocql.UUID) {
+	m.lock.Lock()
+	readers := append([]*streamBatchReader(nil), m.streamReaders...)
+	m.lock.Unlock()
+
+	for _, sbr := range readers {
+		sbr.close(processUntil)
+	}
+}
+
+// Pause pauses every stream of every table. See streamBatchReader.pause for
+// details.
+func (m *MultiTableReader) Pause(ctx context.Context) error {
+	m.lock.Lock()
+	readers := append([]*streamBatchReader(nil), m.streamReaders...)
+	m.lock.Unlock()
+
+	for _, sbr := range readers {
+		sbr.pause()
+	}
+	return nil
+}
+
+// Resume resumes every stream previously paused with Pause.
+func (m *MultiTableReader) Resume(ctx context.Context) error {
+	m.lock.Lock()
+	readers := append([]*streamBatchReader(nil), m.streamReaders...)
+	m.lock.Unlock()
+
+	for _, sbr := range readers {
+		sbr.resume()
+	}
+	return nil
+}
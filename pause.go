@@ -0,0 +1,39 @@
+package scylla_cdc
+
+import "context"
+
+// Pause temporarily stops polling on every stream managed by the reader,
+// without tearing down the configured ChangeConsumers. Each stream
+// checkpoints its current position through the configured ProgressManager
+// before it enters the paused state, so that replication can be continued
+// later with Resume, even across a process restart. Pause only signals the
+// streams and returns without waiting for them to reach the paused state.
+//
+// Pause is a no-op if the reader is already paused.
+func (r *Reader) Pause(ctx context.Context) error {
+	r.lock.Lock()
+	readers := make([]*streamBatchReader, 0, len(r.streamReaders))
+	readers = append(readers, r.streamReaders...)
+	r.lock.Unlock()
+
+	for _, sbr := range readers {
+		sbr.pause()
+	}
+	return nil
+}
+
+// Resume continues a reader previously paused with Pause. Each stream
+// resumes polling from the position it was at when it was paused.
+//
+// Resume is a no-op if the reader is not paused.
+func (r *Reader) Resume(ctx context.Context) error {
+	r.lock.Lock()
+	readers := make([]*streamBatchReader, 0, len(r.streamReaders))
+	readers = append(readers, r.streamReaders...)
+	r.lock.Unlock()
+
+	for _, sbr := range readers {
+		sbr.resume()
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package scylla_cdc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gocql/gocql"
+)
+
+// ProgressManager persists and retrieves the last processed timestamp for
+// a single CDC stream within a given table and generation, so that a
+// streamBatchReader can resume from where it left off instead of starting
+// from the reader's global startFrom position.
+type ProgressManager interface {
+	// SaveProgress stores ts as the last processed timestamp for streamID
+	// in the given table and generation.
+	SaveProgress(ctx context.Context, tableName string, generation gocql.UUID, streamID StreamID, ts gocql.UUID) error
+
+	// GetProgress returns the last saved timestamp for streamID in the
+	// given table and generation. If no progress was ever saved, it
+	// returns the zero gocql.UUID and a nil error.
+	GetProgress(ctx context.Context, tableName string, generation gocql.UUID, streamID StreamID) (gocql.UUID, error)
+}
+
+type progressKey struct {
+	tableName  string
+	generation gocql.UUID
+	streamID   string
+}
+
+// InMemoryProgressManager is a ProgressManager backed by a plain map. It is
+// meant for tests and does not survive process restarts.
+type InMemoryProgressManager struct {
+	mu       sync.Mutex
+	progress map[progressKey]gocql.UUID
+}
+
+// NewInMemoryProgressManager creates an empty InMemoryProgressManager.
+func NewInMemoryProgressManager() *InMemoryProgressManager {
+	return &InMemoryProgressManager{
+		progress: make(map[progressKey]gocql.UUID),
+	}
+}
+
+func (pm *InMemoryProgressManager) SaveProgress(ctx context.Context, tableName string, generation gocql.UUID, streamID StreamID, ts gocql.UUID) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.progress[progressKey{tableName, generation, string(streamID)}] = ts
+	return nil
+}
+
+func (pm *InMemoryProgressManager) GetProgress(ctx context.Context, tableName string, generation gocql.UUID, streamID StreamID) (gocql.UUID, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return pm.progress[progressKey{tableName, generation, string(streamID)}], nil
+}
+
+// ScyllaProgressManager is a ProgressManager which persists progress in a
+// user-configurable table in Scylla, so that it survives process restarts.
+type ScyllaProgressManager struct {
+	session   *gocql.Session
+	tableName string
+}
+
+// NewScyllaProgressManager creates a ScyllaProgressManager which stores
+// progress in tableName, creating the table if it does not already exist.
+// tableName must be fully qualified with a keyspace name.
+func NewScyllaProgressManager(session *gocql.Session, tableName string) (*ScyllaProgressManager, error) {
+	createTableQuery := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s "+
+			"(table_name text, generation timeuuid, stream_id blob, last_timestamp timeuuid, "+
+			"PRIMARY KEY ((table_name, generation), stream_id))",
+		tableName,
+	)
+	if err := session.Query(createTableQuery).Exec(); err != nil {
+		return nil, err
+	}
+
+	return &ScyllaProgressManager{
+		session:   session,
+		tableName: tableName,
+	}, nil
+}
+
+func (pm *ScyllaProgressManager) SaveProgress(ctx context.Context, tableName string, generation gocql.UUID, streamID StreamID, ts gocql.UUID) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (table_name, generation, stream_id, last_timestamp) VALUES (?, ?, ?, ?)",
+		pm.tableName,
+	)
+	return pm.session.Query(query, tableName, generation, []byte(streamID), ts).WithContext(ctx).Exec()
+}
+
+func (pm *ScyllaProgressManager) GetProgress(ctx context.Context, tableName string, generation gocql.UUID, streamID StreamID) (gocql.UUID, error) {
+	query := fmt.Sprintf(
+		"SELECT last_timestamp FROM %s WHERE table_name = ? AND generation = ? AND stream_id = ?",
+		pm.tableName,
+	)
+
+	var ts gocql.UUID
+	err := pm.session.Query(query, tableName, generation, []byte(streamID)).WithContext(ctx).Scan(&ts)
+	if err == gocql.ErrNotFound {
+		return gocql.UUID{}, nil
+	}
+	return ts, err
+}
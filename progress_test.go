@@ -0,0 +1,156 @@
+package scylla_cdc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestInMemoryProgressManagerRoundTrip(t *testing.T) {
+	pm := NewInMemoryProgressManager()
+	ctx := context.Background()
+
+	generation := gocql.TimeUUID()
+	streamA := StreamID("streamA")
+	streamB := StreamID("streamB")
+
+	if ts, err := pm.GetProgress(ctx, "ks.tbl", generation, streamA); err != nil {
+		t.Fatal(err)
+	} else if ts != (gocql.UUID{}) {
+		t.Fatalf("expected zero UUID for unknown stream, got %v", ts)
+	}
+
+	want := gocql.TimeUUID()
+	if err := pm.SaveProgress(ctx, "ks.tbl", generation, streamA, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := pm.GetProgress(ctx, "ks.tbl", generation, streamA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("GetProgress returned %v, want %v", got, want)
+	}
+
+	// A different stream in the same table/generation must not be affected.
+	if ts, err := pm.GetProgress(ctx, "ks.tbl", generation, streamB); err != nil {
+		t.Fatal(err)
+	} else if ts != (gocql.UUID{}) {
+		t.Fatalf("expected zero UUID for streamB, got %v", ts)
+	}
+}
+
+// TestResolveStartFromResumesFromCheckpoint simulates a reader crashing mid-
+// run and restarting: the first run has nothing checkpointed yet and must
+// fall back to Advanced.ChangeAgeLimit, but once a checkpoint has been
+// saved, a fresh resolveStartFrom call for the same stream must resume
+// exactly from it rather than from ChangeAgeLimit again. Resuming from the
+// exact checkpoint (not a coarser fallback) is what prevents a restarted
+// reader from re-delivering changes older than the last acknowledged batch.
+func TestResolveStartFromResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	pm := NewInMemoryProgressManager()
+	config := &ReaderConfig{
+		ProgressManager: pm,
+		Advanced:        AdvancedReaderConfig{ChangeAgeLimit: time.Hour},
+	}
+
+	generation := gocql.TimeUUID()
+	stream := StreamID("stream-a")
+
+	firstStart, err := resolveStartFrom(ctx, config, "ks.tbl", generation, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cutoff := gocql.MinTimeUUID(time.Now().Add(-config.Advanced.ChangeAgeLimit))
+	if CompareTimeuuid(firstStart, cutoff) > 0 {
+		t.Fatalf("expected first run to fall back to ChangeAgeLimit, got a more recent start %v", firstStart)
+	}
+
+	checkpoint := gocql.TimeUUID()
+	if err := pm.SaveProgress(ctx, "ks.tbl", generation, stream, checkpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	restartedStart, err := resolveStartFrom(ctx, config, "ks.tbl", generation, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restartedStart != checkpoint {
+		t.Fatalf("restarted reader should resume from the checkpoint %v, got %v", checkpoint, restartedStart)
+	}
+}
+
+// TestStreamBatchReaderCrashRecovery drives two streamBatchReaders sharing
+// one InMemoryProgressManager through a crash/restart: the first reader
+// checkpoints after each of several batches (what run does at a CDC batch
+// boundary via maybeCheckpointProgress once CheckpointInterval has elapsed),
+// then "crashes" after reading a further batch it never gets to checkpoint.
+// A reader restarted for the same table/generation/stream must resume from
+// the last checkpointed batch boundary — no earlier (duplicates, re-reading
+// already-checkpointed batches) and no later (a gap, skipping changes).
+//
+// This cannot drive streamBatchReader.run() itself: the CDC log querier and
+// ChangeConsumerFactory it depends on (newChangeRowQuerier,
+// changeRowIterator) live outside this snapshot of the repository, so there
+// is nothing to stub a fake query result from. What is exercised here —
+// checkpointProgress and resolveStartFrom — is exactly the pair of
+// operations run and the outer reader use to persist and seed startFrom, so
+// the guarantee under test is the real one, just without the network I/O
+// around it.
+func TestStreamBatchReaderCrashRecovery(t *testing.T) {
+	ctx := context.Background()
+	pm := NewInMemoryProgressManager()
+	config := &ReaderConfig{
+		ProgressManager: pm,
+		Advanced:        AdvancedReaderConfig{ChangeAgeLimit: time.Hour},
+	}
+
+	generation := gocql.TimeUUID()
+	stream := StreamID("stream-a")
+	tableName := "ks.tbl"
+
+	startFrom, err := resolveStartFrom(ctx, config, tableName, generation, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sbr1 := newStreamBatchReader(config, []StreamID{stream}, "ks", "tbl", generation, startFrom)
+
+	batchBoundaries := []gocql.UUID{gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()}
+	for _, boundary := range batchBoundaries {
+		sbr1.lastTimestamp = boundary
+		if err := sbr1.checkpointProgress(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lastCheckpointed := batchBoundaries[len(batchBoundaries)-1]
+
+	// A further batch is read but the process dies before checkpointing it.
+	sbr1.lastTimestamp = gocql.TimeUUID()
+
+	restartFrom, err := resolveStartFrom(ctx, config, tableName, generation, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restartFrom != lastCheckpointed {
+		t.Fatalf("restarted reader resumed from %v, want the last checkpointed boundary %v", restartFrom, lastCheckpointed)
+	}
+
+	sbr2 := newStreamBatchReader(config, []StreamID{stream}, "ks", "tbl", generation, restartFrom)
+
+	// No gap: the restarted reader starts exactly at the last checkpoint,
+	// not somewhere after it.
+	if sbr2.lastTimestamp != lastCheckpointed {
+		t.Fatalf("gap between the crashed reader's last checkpoint and the restarted reader's start")
+	}
+	// No duplicates beyond the batch boundary: earlier, already-checkpointed
+	// batches are strictly before the restarted reader's start.
+	for _, boundary := range batchBoundaries[:len(batchBoundaries)-1] {
+		if CompareTimeuuid(sbr2.lastTimestamp, boundary) <= 0 {
+			t.Fatalf("restarted reader would reprocess already-checkpointed batch at %v", boundary)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package scylla_cdc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// AdvancedReaderConfig groups together tunables which most users should
+// leave at their defaults.
+type AdvancedReaderConfig struct {
+	// ChangeAgeLimit bounds how far in the past a stream starts reading
+	// from when it has no checkpointed progress.
+	ChangeAgeLimit time.Duration
+
+	PostNonEmptyQueryDelay time.Duration
+	PostEmptyQueryDelay    time.Duration
+	PostFailedQueryDelay   time.Duration
+	QueryTimeWindowSize    time.Duration
+	ConfidenceWindowSize   time.Duration
+
+	// CheckpointInterval bounds how often a stream's progress is flushed
+	// through the configured ProgressManager.
+	CheckpointInterval time.Duration
+
+	// GenerationPollInterval controls how often the reader checks for a
+	// new CDC generation while processing the newest known one. Defaults
+	// to one minute.
+	GenerationPollInterval time.Duration
+}
+
+// ReaderConfig configures a Reader or MultiTableReader.
+type ReaderConfig struct {
+	Session               *gocql.Session
+	ChangeConsumerFactory ChangeConsumerFactory
+	Logger                *log.Logger
+	Advanced              AdvancedReaderConfig
+
+	// TableName is the fully qualified (keyspace.table) name of the table
+	// a Reader replicates. Unused by MultiTableReader.
+	TableName string
+
+	// ProgressManager, if set, is used to checkpoint and resume each
+	// stream's position across restarts.
+	ProgressManager ProgressManager
+
+	// Metrics, if set, is notified about the reader's internal behavior.
+	Metrics Metrics
+
+	// Filters, if any, are evaluated against every row before it is
+	// appended to a Change.
+	Filters []ChangeFilter
+
+	// Projection, if set, limits which non-key columns are materialized.
+	Projection Projection
+}
+
+// Reader replicates a single table by reading its CDC log.
+type Reader struct {
+	config       *ReaderConfig
+	keyspaceName string
+	tableName    string
+
+	lock          sync.Mutex
+	streamReaders []*streamBatchReader
+}
+
+// NewReader creates a Reader for the table named by config.TableName.
+func NewReader(config *ReaderConfig) (*Reader, error) {
+	keyspaceName, tableName, err := splitFullyQualifiedTableName(config.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Logger == nil {
+		config.Logger = log.Default()
+	}
+
+	return &Reader{
+		config:       config,
+		keyspaceName: keyspaceName,
+		tableName:    tableName,
+	}, nil
+}
+
+// Run replicates the table until ctx is cancelled, Stop/StopNow is called,
+// or a ChangeConsumer returns an error.
+func (r *Reader) Run(ctx context.Context) error {
+	baseTableName := r.keyspaceName + "." + r.tableName
+
+	return runGenerations(
+		ctx,
+		r.config.Session,
+		r.config.Advanced.GenerationPollInterval,
+		r.config.Logger,
+		func(gen gocql.UUID, nextGen *gocql.UUID) ([]*streamBatchReader, error) {
+			streams, err := fetchStreams(ctx, r.config.Session, gen)
+			if err != nil {
+				return nil, err
+			}
+
+			readers := make([]*streamBatchReader, 0, len(streams))
+			for _, streamID := range streams {
+				startFrom, err := resolveStartFrom(ctx, r.config, baseTableName, gen, streamID)
+				if err != nil {
+					return nil, err
+				}
+
+				sbr := newStreamBatchReader(r.config, []StreamID{streamID}, r.keyspaceName, r.tableName, gen, startFrom)
+				if nextGen != nil {
+					sbr.close(*nextGen)
+				}
+				readers = append(readers, sbr)
+			}
+			return readers, nil
+		},
+		func(readers []*streamBatchReader) {
+			r.lock.Lock()
+			r.streamReaders = readers
+			r.lock.Unlock()
+		},
+	)
+}
+
+// Stop gracefully stops the reader, letting every stream process changes up
+// to the current moment before returning.
+func (r *Reader) Stop() {
+	r.closeReaders(gocql.MinTimeUUID(time.Now()))
+}
+
+// StopNow aborts the reader immediately, without waiting for streams to
+// catch up to the current moment.
+func (r *Reader) StopNow() {
+	r.closeReaders(gocql.UUID{})
+}
+
+func (r *Reader) closeReaders(processUntil gocql.UUID) {
+	r.lock.Lock()
+	readers := append([]*streamBatchReader(nil), r.streamReaders...)
+	r.lock.Unlock()
+
+	for _, sbr := range readers {
+		sbr.close(processUntil)
+	}
+}
+
+func splitFullyQualifiedTableName(name string) (keyspace, table string, err error) {
+	idx := strings.IndexByte(name, '.')
+	if idx < 0 {
+		return "", "", fmt.Errorf("scylla-cdc: table name %q is not of the form keyspace.table", name)
+	}
+	return name[:idx], name[idx+1:], nil
+}
+
+// resolveStartFrom returns the position streamID should resume from: the
+// last checkpoint saved through config's ProgressManager if there is one,
+// otherwise a timestamp Advanced.ChangeAgeLimit in the past.
+func resolveStartFrom(ctx context.Context, config *ReaderConfig, tableName string, gen gocql.UUID, streamID StreamID) (gocql.UUID, error) {
+	if config.ProgressManager != nil {
+		ts, err := config.ProgressManager.GetProgress(ctx, tableName, gen, streamID)
+		if err != nil {
+			return gocql.UUID{}, fmt.Errorf("scylla-cdc: failed to fetch progress for stream: %w", err)
+		}
+		if ts != (gocql.UUID{}) {
+			return ts, nil
+		}
+	}
+	return gocql.MinTimeUUID(time.Now().Add(-config.Advanced.ChangeAgeLimit)), nil
+}
+
+// fetchGenerations returns every known CDC generation's timestamp, ordered
+// from oldest to newest.
+func fetchGenerations(ctx context.Context, session *gocql.Session) ([]gocql.UUID, error) {
+	iter := session.Query(
+		"SELECT time FROM system_distributed.cdc_generation_timestamps WHERE key = 'timestamps'",
+	).WithContext(ctx).Iter()
+
+	var gens []gocql.UUID
+	var t gocql.UUID
+	for iter.Scan(&t) {
+		gens = append(gens, t)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("scylla-cdc: failed to fetch generations: %w", err)
+	}
+
+	sort.Slice(gens, func(i, j int) bool {
+		return CompareTimeuuid(gens[i], gens[j]) < 0
+	})
+	return gens, nil
+}
+
+// fetchStreams returns the stream IDs belonging to the generation gen.
+func fetchStreams(ctx context.Context, session *gocql.Session, gen gocql.UUID) ([]StreamID, error) {
+	iter := session.Query(
+		"SELECT streams FROM system_distributed.cdc_streams_descriptions_v2 WHERE time = ?", gen,
+	).WithContext(ctx).Iter()
+
+	var streams []StreamID
+	var raw [][]byte
+	for iter.Scan(&raw) {
+		for _, s := range raw {
+			streams = append(streams, StreamID(s))
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("scylla-cdc: failed to fetch streams for generation %v: %w", gen, err)
+	}
+	return streams, nil
+}
+
+// runGenerations drives a reader (or a set of readers sharing the same
+// generations, as MultiTableReader does) through the cluster's sequence of
+// CDC generations. makeReaders is called once per generation to build the
+// streamBatchReaders that should run during it; setActiveReaders is called
+// whenever the set of currently-running readers changes, so that the
+// caller's Pause/Stop/etc. methods can find them.
+//
+// While processing the newest known generation, runGenerations polls for a
+// new one to appear and, once it does, tells the current generation's
+// readers to wind down at the new generation's start so that every table
+// rotates atomically.
+func runGenerations(
+	ctx context.Context,
+	session *gocql.Session,
+	pollInterval time.Duration,
+	logger *log.Logger,
+	makeReaders func(gen gocql.UUID, nextGen *gocql.UUID) ([]*streamBatchReader, error),
+	setActiveReaders func([]*streamBatchReader),
+) error {
+	gens, err := fetchGenerations(ctx, session)
+	if err != nil {
+		return err
+	}
+	if len(gens) == 0 {
+		return fmt.Errorf("scylla-cdc: no CDC generations found")
+	}
+
+	if pollInterval <= 0 {
+		pollInterval = time.Minute
+	}
+
+	for idx := 0; ; idx++ {
+		gen := gens[idx]
+
+		var nextGen *gocql.UUID
+		if idx+1 < len(gens) {
+			g := gens[idx+1]
+			nextGen = &g
+		}
+
+		readers, err := makeReaders(gen, nextGen)
+		if err != nil {
+			return err
+		}
+		setActiveReaders(readers)
+
+		runDone := make(chan error, 1)
+		runnersFinished := make(chan struct{})
+		go func() {
+			runDone <- runReaders(ctx, readers)
+			close(runnersFinished)
+		}()
+
+		if nextGen == nil {
+			gens = waitForRotationOrDone(ctx, session, logger, pollInterval, gens, readers, runnersFinished)
+		}
+
+		if err := <-runDone; err != nil {
+			setActiveReaders(nil)
+			return err
+		}
+		setActiveReaders(nil)
+
+		if idx+1 >= len(gens) {
+			return nil
+		}
+	}
+}
+
+// runReaders runs every streamBatchReader in readers concurrently and
+// returns the first error any of them reports, if any. As soon as one of
+// them fails, the others are cancelled.
+func runReaders(ctx context.Context, readers []*streamBatchReader) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, len(readers))
+	for _, sbr := range readers {
+		sbr := sbr
+		go func() {
+			_, err := sbr.run(ctx)
+			errCh <- err
+		}()
+	}
+
+	var firstErr error
+	for range readers {
+		if err := <-errCh; err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	return firstErr
+}
+
+// waitForRotationOrDone polls for a new CDC generation to appear while
+// readers (belonging to the last known generation, gens[len(gens)-1]) are
+// running, and returns the updated generation list once one appears. It
+// stops polling as soon as runnersFinished is closed or ctx is cancelled,
+// without ever touching the channel runGenerations uses to collect the run
+// error.
+func waitForRotationOrDone(
+	ctx context.Context,
+	session *gocql.Session,
+	logger *log.Logger,
+	pollInterval time.Duration,
+	gens []gocql.UUID,
+	readers []*streamBatchReader,
+	runnersFinished <-chan struct{},
+) []gocql.UUID {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return gens
+		case <-runnersFinished:
+			return gens
+		case <-ticker.C:
+			newGens, err := fetchGenerations(ctx, session)
+			if err != nil {
+				logger.Printf("error while polling for new generations (will retry): %s", err)
+				continue
+			}
+			if len(newGens) > len(gens) {
+				for _, sbr := range readers {
+					sbr.close(newGens[len(gens)])
+				}
+				return newGens
+			}
+		}
+	}
+}
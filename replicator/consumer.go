@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gocql/gocql"
+	scylla_cdc "github.com/piodul/scylla-cdc-go"
+)
+
+// replicatorFactory creates replicatorConsumers, one per group of streams,
+// all targeting the same destination table.
+type replicatorFactory struct {
+	session *gocql.Session
+
+	tableName  string
+	pkColumns  []string
+	keyColumns []string // pkColumns followed by the clustering columns
+	columns    []string
+}
+
+func newReplicatorFactory(session *gocql.Session, keyspace, table string) (*replicatorFactory, error) {
+	meta, err := session.KeyspaceMetadata(keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("replicator: failed to fetch metadata for keyspace %s: %w", keyspace, err)
+	}
+
+	tableMeta, ok := meta.Tables[table]
+	if !ok {
+		return nil, fmt.Errorf("replicator: table %s.%s does not exist in the destination cluster", keyspace, table)
+	}
+
+	var pkColumns, keyColumns, columns []string
+	for _, c := range tableMeta.PartitionKey {
+		pkColumns = append(pkColumns, c.Name)
+	}
+	keyColumns = append(keyColumns, pkColumns...)
+	for _, c := range tableMeta.ClusteringColumns {
+		keyColumns = append(keyColumns, c.Name)
+	}
+	for name := range tableMeta.Columns {
+		columns = append(columns, name)
+	}
+
+	return &replicatorFactory{
+		session:    session,
+		tableName:  keyspace + "." + table,
+		pkColumns:  pkColumns,
+		keyColumns: keyColumns,
+		columns:    columns,
+	}, nil
+}
+
+func (f *replicatorFactory) CreateChangeConsumer(input scylla_cdc.CreateChangeConsumerInput) (scylla_cdc.ChangeConsumer, error) {
+	return &replicatorConsumer{factory: f}, nil
+}
+
+// replicatorConsumer applies changes read from the source table's CDC log
+// onto the same table in the destination cluster.
+type replicatorConsumer struct {
+	factory *replicatorFactory
+}
+
+func (rc *replicatorConsumer) Consume(c scylla_cdc.Change) error {
+	// A clustering range delete is split across two delta rows: a start
+	// marker followed by an end marker. Neither carries enough information
+	// to build a DELETE on its own, so the start marker is held until its
+	// matching end marker arrives.
+	var rangeStart *scylla_cdc.ChangeRow
+
+	for _, row := range c.Delta {
+		switch row.GetOperation() {
+		case scylla_cdc.RangeDeleteStartInclusive, scylla_cdc.RangeDeleteStartExclusive:
+			rangeStart = row
+		case scylla_cdc.RangeDeleteEndInclusive, scylla_cdc.RangeDeleteEndExclusive:
+			if rangeStart == nil {
+				return fmt.Errorf("replicator: range delete end marker without a matching start marker")
+			}
+			err := rc.execRangeDelete(rangeStart, row)
+			rangeStart = nil
+			if err != nil {
+				return err
+			}
+		default:
+			if err := rc.applyRow(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (rc *replicatorConsumer) End() error {
+	return nil
+}
+
+func (rc *replicatorConsumer) applyRow(row *scylla_cdc.ChangeRow) error {
+	f := rc.factory
+
+	switch row.GetOperation() {
+	case scylla_cdc.RowDelete:
+		return rc.execDelete(row, f.keyColumns)
+	case scylla_cdc.PartitionDelete:
+		return rc.execDelete(row, f.pkColumns)
+	default:
+		return rc.execUpsert(row)
+	}
+}
+
+func (rc *replicatorConsumer) execUpsert(row *scylla_cdc.ChangeRow) error {
+	f := rc.factory
+
+	assignments := make([]string, 0, len(f.columns))
+	values := make([]interface{}, 0, len(f.columns)+len(f.keyColumns))
+
+	for _, col := range f.columns {
+		if isKeyColumn(col, f.keyColumns) || !row.IsColumnSet(col) {
+			continue
+		}
+		assignments = append(assignments, col+" = ?")
+		values = append(values, row.GetValue(col))
+	}
+
+	if len(assignments) == 0 {
+		// The change only touched the primary key (a bare INSERT with no
+		// other columns set); make sure the row still gets created.
+		keyValues := make([]interface{}, 0, len(f.keyColumns))
+		for _, col := range f.keyColumns {
+			keyValues = append(keyValues, row.GetValue(col))
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(f.keyColumns)), ", ")
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", f.tableName, strings.Join(f.keyColumns, ", "), placeholders)
+		return f.session.Query(query, keyValues...).Exec()
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		f.tableName,
+		strings.Join(assignments, ", "),
+		whereClause(f.keyColumns),
+	)
+	for _, col := range f.keyColumns {
+		values = append(values, row.GetValue(col))
+	}
+
+	return f.session.Query(query, values...).Exec()
+}
+
+func (rc *replicatorConsumer) execDelete(row *scylla_cdc.ChangeRow, keyColumns []string) error {
+	f := rc.factory
+
+	values := make([]interface{}, 0, len(keyColumns))
+	for _, col := range keyColumns {
+		values = append(values, row.GetValue(col))
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", f.tableName, whereClause(keyColumns))
+	return f.session.Query(query, values...).Exec()
+}
+
+// execRangeDelete translates a pair of range-delete marker rows into a
+// single ranged DELETE. Each marker carries the partition key plus a prefix
+// of clustering columns; the last clustering column set on a marker is the
+// one the range is bounded on, with the marker's operation type (Inclusive
+// vs Exclusive, Start vs End) deciding the comparison operator. A marker
+// with no clustering columns set at all means that side of the range is
+// unbounded (e.g. "ck1 > 3" with no upper bound).
+func (rc *replicatorConsumer) execRangeDelete(start, end *scylla_cdc.ChangeRow) error {
+	f := rc.factory
+	clusteringColumns := f.keyColumns[len(f.pkColumns):]
+
+	conditions := make([]string, 0, len(f.keyColumns))
+	values := make([]interface{}, 0, len(f.keyColumns))
+
+	for _, col := range f.pkColumns {
+		conditions = append(conditions, col+" = ?")
+		values = append(values, start.GetValue(col))
+	}
+
+	for _, bound := range [...]*scylla_cdc.ChangeRow{start, end} {
+		boundConditions, boundValues, err := rangeBoundConditions(bound, clusteringColumns)
+		if err != nil {
+			return err
+		}
+		conditions = append(conditions, boundConditions...)
+		values = append(values, boundValues...)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", f.tableName, strings.Join(conditions, " AND "))
+	return f.session.Query(query, values...).Exec()
+}
+
+// rangeBoundConditions builds the WHERE conditions contributed by one side
+// of a range delete: an equality condition for every clustering column set
+// on bound except the last, and a single inequality condition for the last
+// one, using the operator that matches bound's operation type.
+func rangeBoundConditions(bound *scylla_cdc.ChangeRow, clusteringColumns []string) ([]string, []interface{}, error) {
+	lastSet := -1
+	for i, col := range clusteringColumns {
+		if !bound.IsColumnSet(col) {
+			break
+		}
+		lastSet = i
+	}
+	if lastSet < 0 {
+		return nil, nil, nil
+	}
+
+	operator, err := rangeBoundOperator(bound.GetOperation())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conditions := make([]string, 0, lastSet+1)
+	values := make([]interface{}, 0, lastSet+1)
+	for i := 0; i < lastSet; i++ {
+		conditions = append(conditions, clusteringColumns[i]+" = ?")
+		values = append(values, bound.GetValue(clusteringColumns[i]))
+	}
+	conditions = append(conditions, clusteringColumns[lastSet]+" "+operator+" ?")
+	values = append(values, bound.GetValue(clusteringColumns[lastSet]))
+
+	return conditions, values, nil
+}
+
+func rangeBoundOperator(op scylla_cdc.OperationType) (string, error) {
+	switch op {
+	case scylla_cdc.RangeDeleteStartInclusive:
+		return ">=", nil
+	case scylla_cdc.RangeDeleteStartExclusive:
+		return ">", nil
+	case scylla_cdc.RangeDeleteEndInclusive:
+		return "<=", nil
+	case scylla_cdc.RangeDeleteEndExclusive:
+		return "<", nil
+	default:
+		return "", fmt.Errorf("replicator: operation %v is not a range delete bound", op)
+	}
+}
+
+func whereClause(keyColumns []string) string {
+	parts := make([]string, len(keyColumns))
+	for i, col := range keyColumns {
+		parts[i] = col + " = ?"
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func isKeyColumn(col string, keyColumns []string) bool {
+	for _, k := range keyColumns {
+		if k == col {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// Command replicator is an example application which replicates a set of
+// tables from a source Scylla cluster to a destination cluster using the
+// scylla_cdc library.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gocql/gocql"
+	scylla_cdc "github.com/piodul/scylla-cdc-go"
+)
+
+// RunReplicator starts replicating every table in tableNames from source to
+// destination, returning a function which stops the replication and waits
+// for it to finish.
+func RunReplicator(
+	ctx context.Context,
+	source, destination string,
+	tableNames []string,
+	adv *scylla_cdc.AdvancedReaderConfig,
+) (func() error, error) {
+	sourceSession, err := gocql.NewCluster(source).CreateSession()
+	if err != nil {
+		return nil, fmt.Errorf("replicator: failed to connect to source: %w", err)
+	}
+
+	destinationSession, err := gocql.NewCluster(destination).CreateSession()
+	if err != nil {
+		sourceSession.Close()
+		return nil, fmt.Errorf("replicator: failed to connect to destination: %w", err)
+	}
+
+	tables := make([]scylla_cdc.TableReplicationConfig, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		keyspace, table, err := splitTableName(tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		factory, err := newReplicatorFactory(destinationSession, keyspace, table)
+		if err != nil {
+			return nil, err
+		}
+
+		tables = append(tables, scylla_cdc.TableReplicationConfig{
+			Keyspace:              keyspace,
+			Table:                 table,
+			ChangeConsumerFactory: factory,
+		})
+	}
+
+	config := &scylla_cdc.ReaderConfig{
+		Session: sourceSession,
+		Logger:  log.Default(),
+	}
+	if adv != nil {
+		config.Advanced = *adv
+	}
+
+	reader, err := scylla_cdc.NewMultiTableReader(config, tables)
+	if err != nil {
+		sourceSession.Close()
+		destinationSession.Close()
+		return nil, err
+	}
+
+	runErrCh := make(chan error, 1)
+	go func() {
+		runErrCh <- reader.Run(ctx)
+	}()
+
+	finishF := func() error {
+		reader.Stop()
+		err := <-runErrCh
+		sourceSession.Close()
+		destinationSession.Close()
+		return err
+	}
+
+	return finishF, nil
+}
+
+func splitTableName(tableName string) (keyspace, table string, err error) {
+	parts := strings.SplitN(tableName, ".", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("replicator: table name %q is not of the form keyspace.table", tableName)
+	}
+	return parts[0], parts[1], nil
+}
@@ -13,11 +13,21 @@ type streamBatchReader struct {
 	streams      []StreamID
 	keyspaceName string
 	tableName    string
+	generation   gocql.UUID
 
-	lastTimestamp gocql.UUID
-	endTimestamp  atomic.Value
+	lastTimestamp  gocql.UUID
+	endTimestamp   atomic.Value
+	lastCheckpoint time.Time
 
 	interruptCh chan struct{}
+	pauseCh     chan struct{}
+	resumeCh    chan struct{}
+
+	// paused is 1 while the reader is paused, guarding pause/resume so that
+	// they are idempotent and never block: a second pause() while already
+	// paused, or a pause()/resume() after run() has returned, must not wait
+	// on a channel nothing will ever drain again.
+	paused int32
 }
 
 func newStreamBatchReader(
@@ -25,6 +35,7 @@ func newStreamBatchReader(
 	streams []StreamID,
 	keyspaceName string,
 	tableName string,
+	generation gocql.UUID,
 	startFrom gocql.UUID,
 ) *streamBatchReader {
 	return &streamBatchReader{
@@ -32,10 +43,13 @@ func newStreamBatchReader(
 		streams:      streams,
 		keyspaceName: keyspaceName,
 		tableName:    tableName,
+		generation:   generation,
 
 		lastTimestamp: startFrom,
 
 		interruptCh: make(chan struct{}, 1),
+		pauseCh:     make(chan struct{}, 1),
+		resumeCh:    make(chan struct{}, 1),
 	}
 }
 
@@ -52,11 +66,30 @@ func (sbr *streamBatchReader) run(ctx context.Context) (gocql.UUID, error) {
 	}
 	defer consumer.End()
 
-	crq := newChangeRowQuerier(sbr.config.Session, sbr.streams, sbr.keyspaceName, sbr.tableName)
+	crq := newChangeRowQuerier(sbr.config.Session, sbr.streams, sbr.keyspaceName, sbr.tableName, sbr.config.Projection)
+
+	if sbr.config.Metrics != nil {
+		sbr.config.Metrics.IncActiveStreamWorkers(baseTableName)
+		defer sbr.config.Metrics.DecActiveStreamWorkers(baseTableName)
+	}
 
 	// sbr.config.Logger.Printf("starting stream processor loop for %v", sbr.streams)
 outer:
 	for {
+		select {
+		case <-sbr.pauseCh:
+			if err := sbr.checkpointProgress(ctx); err != nil {
+				sbr.config.Logger.Printf("error while flushing progress before pause (will continue): %s", err)
+			}
+			if err := sbr.waitWhilePaused(ctx); err != nil {
+				return sbr.lastTimestamp, err
+			}
+		default:
+		}
+
+		// timeWindowEnd is recomputed on every iteration from lastTimestamp,
+		// capped by the current time, so a resumed reader naturally picks up
+		// polling from where it left off.
 		timeWindowEnd := sbr.lastTimestamp.Time().Add(sbr.config.Advanced.QueryTimeWindowSize)
 		confidenceWindowEnd := time.Now().Add(-sbr.config.Advanced.ConfidenceWindowSize)
 
@@ -75,43 +108,80 @@ outer:
 
 		if CompareTimeuuid(sbr.lastTimestamp, pollEnd) < 0 {
 			// Set the time interval from which we need to return data
+			queryStart := time.Now()
 			var iter *changeRowIterator
 			iter, err = crq.queryRange(sbr.lastTimestamp, pollEnd)
 			if err != nil {
 				sbr.config.Logger.Printf("error while sending a query (will retry): %s", err)
+				if sbr.config.Metrics != nil {
+					sbr.config.Metrics.IncQueryErrors(baseTableName)
+				}
 			} else {
 				var change Change
+				var deltaRowSeen, deltaRowMatched bool
 				for {
 					streamCols, c := iter.Next()
 					if c == nil {
 						break
 					}
 
-					if c.GetOperation() == PreImage {
-						change.Preimage = append(change.Preimage, c)
-					} else if c.GetOperation() == PostImage {
-						change.Postimage = append(change.Postimage, c)
-					} else {
-						change.Delta = append(change.Delta, c)
+					matched := sbr.matchesFilters(streamCols, c)
+
+					switch c.GetOperation() {
+					case PreImage:
+						if matched {
+							change.Preimage = append(change.Preimage, c)
+						}
+					case PostImage:
+						if matched {
+							change.Postimage = append(change.Postimage, c)
+						}
+					default:
+						deltaRowSeen = true
+						if matched {
+							deltaRowMatched = true
+							change.Delta = append(change.Delta, c)
+						}
 					}
 
 					if c.cdcCols.endOfBatch {
-						change.StreamID = streamCols.streamID
-						change.Time = streamCols.time
-						if err := consumer.Consume(change); err != nil {
-							// TODO: Does that make sense?
-							sbr.config.Logger.Printf("error while processing change (will quit): %s", err)
-							return sbr.lastTimestamp, err
+						// Skip the whole batch if it had delta rows but none
+						// of them passed the configured filters.
+						if !deltaRowSeen || deltaRowMatched {
+							change.StreamID = streamCols.streamID
+							change.Time = streamCols.time
+
+							consumeStart := time.Now()
+							consumeErr := consumer.Consume(change)
+							if sbr.config.Metrics != nil {
+								sbr.config.Metrics.ObserveConsumeLatency(baseTableName, time.Since(consumeStart))
+							}
+							if consumeErr != nil {
+								// TODO: Does that make sense?
+								sbr.config.Logger.Printf("error while processing change (will quit): %s", consumeErr)
+								return sbr.lastTimestamp, consumeErr
+							}
+
+							if sbr.config.Metrics != nil {
+								sbr.config.Metrics.IncChangesConsumed(baseTableName, 1)
+								sbr.config.Metrics.SetLastReadTimestamp(baseTableName, streamCols.time.Time())
+							}
 						}
 
 						change.Preimage = nil
 						change.Delta = nil
 						change.Postimage = nil
+						deltaRowSeen = false
+						deltaRowMatched = false
 
 						// Update the last timestamp only after we processed whole batch
 						if CompareTimeuuid(sbr.lastTimestamp, streamCols.time) < 0 {
 							sbr.lastTimestamp = streamCols.time
 						}
+
+						if err := sbr.maybeCheckpointProgress(ctx); err != nil {
+							sbr.config.Logger.Printf("error while saving progress (will retry later): %s", err)
+						}
 					}
 
 					rowCount++
@@ -119,6 +189,14 @@ outer:
 
 				if err = iter.Close(); err != nil {
 					sbr.config.Logger.Printf("error while querying (will retry): %s", err)
+					if sbr.config.Metrics != nil {
+						sbr.config.Metrics.IncQueryErrors(baseTableName)
+					}
+				}
+
+				if sbr.config.Metrics != nil {
+					sbr.config.Metrics.ObserveQueryLatency(baseTableName, time.Since(queryStart))
+					sbr.config.Metrics.IncRowsRead(baseTableName, rowCount)
 				}
 			}
 		} else {
@@ -177,3 +255,94 @@ func (sbr *streamBatchReader) close(processUntil gocql.UUID) {
 func (sbr *streamBatchReader) stopNow() {
 	sbr.close(gocql.UUID{})
 }
+
+// waitWhilePaused blocks until the reader is resumed, closed (Stop/StopNow),
+// or ctx is cancelled. It must also watch interruptCh, not just resumeCh:
+// close() only signals interruptCh, so without this a reader paused when
+// Stop/StopNow is called would block here forever, and run would never
+// return. An interrupt unblocks the wait the same way a resume would,
+// leaving the usual reachedEndOfTheGeneration check further down in run to
+// decide whether that means the generation has actually ended, exactly as
+// an interrupt received during the post-poll delay already does.
+func (sbr *streamBatchReader) waitWhilePaused(ctx context.Context) error {
+	select {
+	case <-sbr.resumeCh:
+		return nil
+	case <-sbr.interruptCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause asks run to stop issuing queries and block until resume is called,
+// without tearing down the ChangeConsumer. Progress is checkpointed before
+// the reader blocks.
+//
+// pause is a no-op if the reader is already paused, and never blocks even if
+// run has already returned.
+func (sbr *streamBatchReader) pause() {
+	if !atomic.CompareAndSwapInt32(&sbr.paused, 0, 1) {
+		return
+	}
+	select {
+	case sbr.pauseCh <- struct{}{}:
+	default:
+	}
+}
+
+// resume unblocks a reader previously paused with pause.
+//
+// resume is a no-op if the reader is not paused, and never blocks even if
+// run has already returned.
+func (sbr *streamBatchReader) resume() {
+	if !atomic.CompareAndSwapInt32(&sbr.paused, 1, 0) {
+		return
+	}
+	select {
+	case sbr.resumeCh <- struct{}{}:
+	default:
+	}
+}
+
+// matchesFilters reports whether c passes every configured ChangeFilter. A
+// reader with no filters configured matches every row.
+func (sbr *streamBatchReader) matchesFilters(streamCols streamColumns, c *ChangeRow) bool {
+	for _, f := range sbr.config.Filters {
+		if !f.Match(streamCols, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// maybeCheckpointProgress saves the current position of all streams handled
+// by this reader through the configured ProgressManager, but only if
+// Advanced.CheckpointInterval has elapsed since the last save. This keeps
+// the write amplification of checkpointing bounded.
+func (sbr *streamBatchReader) maybeCheckpointProgress(ctx context.Context) error {
+	if sbr.config.ProgressManager == nil {
+		return nil
+	}
+	if time.Since(sbr.lastCheckpoint) < sbr.config.Advanced.CheckpointInterval {
+		return nil
+	}
+	return sbr.checkpointProgress(ctx)
+}
+
+// checkpointProgress unconditionally flushes the current position of all
+// streams handled by this reader through the configured ProgressManager.
+func (sbr *streamBatchReader) checkpointProgress(ctx context.Context) error {
+	if sbr.config.ProgressManager == nil {
+		return nil
+	}
+
+	baseTableName := sbr.keyspaceName + "." + sbr.tableName
+	for _, streamID := range sbr.streams {
+		if err := sbr.config.ProgressManager.SaveProgress(ctx, baseTableName, sbr.generation, streamID, sbr.lastTimestamp); err != nil {
+			return err
+		}
+	}
+	sbr.lastCheckpoint = time.Now()
+	return nil
+}
@@ -0,0 +1,63 @@
+package scylla_cdc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// TestStreamBatchReaderPauseResumeIdempotent exercises pause/resume directly
+// against the reader's channels, without running the reader. It guards
+// against the failure mode where pause()/resume() rely on channel buffer
+// capacity for idempotency: calling pause() twice must not leave a second
+// buffered signal that would fire again right after the following resume(),
+// and neither call may ever block.
+func TestStreamBatchReaderPauseResumeIdempotent(t *testing.T) {
+	sbr := newStreamBatchReader(&ReaderConfig{}, nil, "ks", "tbl", gocql.UUID{}, gocql.UUID{})
+
+	sbr.pause()
+	sbr.pause() // no-op: already paused, must not block and must not double-buffer
+	if got := len(sbr.pauseCh); got != 1 {
+		t.Fatalf("pauseCh should have exactly one buffered signal, got %d", got)
+	}
+
+	<-sbr.pauseCh // drain it the way run's select would
+
+	sbr.resume()
+	sbr.resume() // no-op: already resumed, must not block and must not double-buffer
+	if got := len(sbr.resumeCh); got != 1 {
+		t.Fatalf("resumeCh should have exactly one buffered signal, got %d", got)
+	}
+}
+
+// TestStreamBatchReaderPauseThenCloseDoesNotDeadlock reproduces the
+// sequence run's caller is free to issue at any time: pause a reader, then
+// decide to stop it (Stop/StopNow, both backed by close()). Before this
+// fix, waitWhilePaused only watched resumeCh and ctx.Done(), so close()
+// (which only signals interruptCh) would leave run blocked forever and any
+// caller doing Stop(); <-runErrCh — as replicator/main.go's finishF does —
+// would hang indefinitely.
+func TestStreamBatchReaderPauseThenCloseDoesNotDeadlock(t *testing.T) {
+	sbr := newStreamBatchReader(&ReaderConfig{}, nil, "ks", "tbl", gocql.UUID{}, gocql.UUID{})
+
+	sbr.pause()
+	<-sbr.pauseCh // as run's select would, entering the paused wait
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sbr.waitWhilePaused(context.Background())
+	}()
+
+	sbr.close(gocql.UUID{}) // what Stop()/StopNow() do
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("waitWhilePaused returned error %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waitWhilePaused deadlocked after close() while paused")
+	}
+}